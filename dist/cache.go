@@ -0,0 +1,146 @@
+package dist
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache is a content-addressed store of built/downloaded plugin artifacts,
+// rooted at a directory of the form <dir>/sha256:<digest>/plugin.so. It also
+// tracks which plugin key resolved to which digest, so Remove can garbage
+// collect an artifact once nothing references it anymore.
+type Cache struct {
+	dir string
+
+	mu    sync.Mutex
+	index map[string]string // plugin key -> digest
+}
+
+// NewCache returns a Cache rooted at dir, creating it if it doesn't exist.
+// The on-disk index of key -> digest is loaded if present.
+func NewCache(dir string) (c *Cache, err error) {
+	if dir == "" {
+		return nil, fmt.Errorf("cache directory cannot be empty")
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating cache directory <%s>: %v", dir, err)
+	}
+
+	c = &Cache{dir: dir, index: make(map[string]string)}
+	if err = c.loadIndex(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Cache) indexPath() string {
+	return filepath.Join(c.dir, "index.json")
+}
+
+func (c *Cache) loadIndex() error {
+	body, err := os.ReadFile(c.indexPath())
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("error reading cache index: %v", err)
+	}
+
+	if err = json.Unmarshal(body, &c.index); err != nil {
+		return fmt.Errorf("error parsing cache index: %v", err)
+	}
+
+	return nil
+}
+
+// saveIndex persists the index. The caller must hold c.mu.
+func (c *Cache) saveIndex() error {
+	body, err := json.Marshal(c.index)
+	if err != nil {
+		return fmt.Errorf("error encoding cache index: %v", err)
+	}
+
+	if err = os.WriteFile(c.indexPath(), body, 0644); err != nil {
+		return fmt.Errorf("error writing cache index: %v", err)
+	}
+
+	return nil
+}
+
+// entryDir returns the directory an artifact with the given digest lives in.
+func (c *Cache) entryDir(digest string) string {
+	return filepath.Join(c.dir, "sha256:"+digest)
+}
+
+// ArtifactPath returns where the plugin.so for digest lives, whether or not
+// it has been stored yet.
+func (c *Cache) ArtifactPath(digest string) string {
+	return filepath.Join(c.entryDir(digest), "plugin.so")
+}
+
+// Has reports whether an artifact with the given digest is already cached.
+func (c *Cache) Has(digest string) bool {
+	_, err := os.Stat(c.ArtifactPath(digest))
+	return err == nil
+}
+
+// Store writes artifact under its content address and records that key
+// resolves to it, returning the path it was written to (or already existed
+// at, if another key already produced the same digest).
+func (c *Cache) Store(key, digest string, artifact []byte) (path string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path = c.ArtifactPath(digest)
+	if !c.Has(digest) {
+		if err = os.MkdirAll(c.entryDir(digest), 0755); err != nil {
+			return "", fmt.Errorf("error creating cache entry for <%s>: %v", digest, err)
+		}
+
+		if err = os.WriteFile(path, artifact, 0644); err != nil {
+			return "", fmt.Errorf("error writing cache entry for <%s>: %v", digest, err)
+		}
+	}
+
+	c.index[key] = digest
+	if err = c.saveIndex(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Remove drops key's entry from the index and, if no other key still
+// references the same digest, deletes the cached artifact too.
+func (c *Cache) Remove(key string) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	digest, ok := c.index[key]
+	if !ok {
+		return nil
+	}
+
+	delete(c.index, key)
+	if err = c.saveIndex(); err != nil {
+		return err
+	}
+
+	for _, d := range c.index {
+		if d == digest {
+			// another plugin key still references this artifact
+			return nil
+		}
+	}
+
+	if err = os.RemoveAll(c.entryDir(digest)); err != nil {
+		return fmt.Errorf("error removing cache entry for <%s>: %v", digest, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package dist
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCache_Remove_refcounting(t *testing.T) {
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifact := []byte("shared artifact")
+	digest := sha256Hex(artifact)
+
+	path, err := cache.Store("a", digest, artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = cache.Store("b", digest, artifact); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = cache.Remove("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(path); err != nil {
+		t.Fatalf("expected artifact to survive while b still references it, got: %v", err)
+	}
+
+	if err = cache.Remove("b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected artifact to be removed once no key references it, got: %v", err)
+	}
+}
+
+func TestCache_Remove_unknownKey(t *testing.T) {
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = cache.Remove("never-registered"); err != nil {
+		t.Fatalf("expected removing an unknown key to be a no-op, got: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package dist
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Downloader fetches a prebuilt artifact and its detached signature for a
+// plugin key. Callers that need a private registry implement this instead of
+// using HTTPDownloader.
+type Downloader interface {
+	// Download returns the plugin.so contents and its detached signature for
+	// key. Implementations that don't sign artifacts may return a nil
+	// signature, but then Resolver must be configured with a nil Verifier.
+	Download(key string) (artifact, signature []byte, err error)
+}
+
+// HTTPDownloader fetches prebuilt artifacts from an HTTP(S) registry, using
+// <BaseURL>/<key>.so and <BaseURL>/<key>.so.minisig as the artifact and
+// signature URLs.
+type HTTPDownloader struct {
+	// BaseURL is the registry root, e.g. "https://plugins.example.com".
+	BaseURL string
+	// Client is used to make requests. http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+// Download implements Downloader.
+func (d HTTPDownloader) Download(key string) (artifact, signature []byte, err error) {
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	base := strings.TrimSuffix(d.BaseURL, "/")
+	if artifact, err = d.get(client, base+"/"+key+".so"); err != nil {
+		return nil, nil, err
+	}
+
+	if signature, err = d.get(client, base+"/"+key+".so.minisig"); err != nil {
+		return nil, nil, err
+	}
+
+	return artifact, signature, nil
+}
+
+func (d HTTPDownloader) get(client *http.Client, url string) (body []byte, err error) {
+	var resp *http.Response
+	if resp, err = client.Get(url); err != nil {
+		return nil, fmt.Errorf("error fetching <%s>: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching <%s>: unexpected status %s", url, resp.Status)
+	}
+
+	if body, err = io.ReadAll(resp.Body); err != nil {
+		return nil, fmt.Errorf("error reading <%s>: %v", url, err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,47 @@
+package dist
+
+import (
+	"os"
+	"testing"
+)
+
+// fakeDownloader always returns the same artifact bytes, regardless of key,
+// so two different keys resolve to the same content-addressed digest.
+type fakeDownloader struct {
+	artifact []byte
+}
+
+func (d fakeDownloader) Download(key string) (artifact, signature []byte, err error) {
+	return d.artifact, nil, nil
+}
+
+func TestResolver_Resolve_recordsEveryKeyOnCacheHit(t *testing.T) {
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Resolver{Cache: cache, Downloader: fakeDownloader{artifact: []byte("identical artifact bytes")}}
+
+	pathA, err := r.Resolve("github.com/user/repo/a@v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pathB, err := r.Resolve("github.com/user/repo/b@v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pathA != pathB {
+		t.Fatalf("expected byte-identical artifacts to share a cache path, got %q and %q", pathA, pathB)
+	}
+
+	if err := cache.Remove("github.com/user/repo/a@v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(pathB); err != nil {
+		t.Fatalf("expected b's artifact to still exist after removing a, got: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package dist
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+// Verifier checks an artifact against a detached signature fetched alongside
+// it. A nil Verifier on Resolver disables verification entirely, which is
+// only appropriate for a Downloader that already establishes trust some
+// other way (e.g. a private registry reached over an authenticated channel).
+type Verifier interface {
+	Verify(artifact, signature []byte) error
+}
+
+// Ed25519Verifier verifies a detached ed25519 signature, given as the raw
+// signature bytes over the artifact, against a fixed public key. It does not
+// parse minisign's text signature file format.
+type Ed25519Verifier struct {
+	PublicKey ed25519.PublicKey
+}
+
+// Verify implements Verifier.
+func (v Ed25519Verifier) Verify(artifact, signature []byte) error {
+	if len(v.PublicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid ed25519 public key, expected %d bytes and got %d", ed25519.PublicKeySize, len(v.PublicKey))
+	}
+
+	if !ed25519.Verify(v.PublicKey, artifact, signature) {
+		return fmt.Errorf("artifact signature verification failed")
+	}
+
+	return nil
+}
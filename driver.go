@@ -0,0 +1,12 @@
+package plugins
+
+// Driver loads a Plugin implementation for a given key. The two backends this
+// package ships are the in-process GoloaderDriver (which loads
+// compiled objects via goloader rather than Go's own plugin package, sidestepping
+// its -buildmode=plugin platform restrictions) and RPCDriver, which runs the
+// plugin as a separate OS process at the cost of an RPC hop.
+type Driver interface {
+	// Load starts (or loads) the plugin identified by key and returns the
+	// Plugin used to drive it.
+	Load(key string) (Plugin, error)
+}
@@ -0,0 +1,142 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Status is the outcome of a plugin's health check.
+type Status uint8
+
+const (
+	// StatusOk indicates the plugin is healthy.
+	StatusOk Status = iota
+	// StatusWarning indicates the plugin is degraded but still usable.
+	StatusWarning
+	// StatusError indicates the plugin is unhealthy.
+	StatusError
+)
+
+// String implements fmt.Stringer.
+func (s Status) String() string {
+	switch s {
+	case StatusOk:
+		return "ok"
+	case StatusWarning:
+		return "warning"
+	case StatusError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthChecker is implemented by plugins which can report their own health.
+// Plugins.Test and Plugins.TestAsync skip plugins that don't implement it.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) (Status, error)
+}
+
+// Health is a single plugin's health check result.
+type Health struct {
+	Key      string
+	Status   Status
+	Message  string
+	Duration time.Duration
+}
+
+// Report aggregates the health of every checked plugin.
+type Report struct {
+	Results []Health
+}
+
+// Status returns the worst status across every result, or StatusOk if there
+// were none.
+func (r Report) Status() Status {
+	worst := StatusOk
+	for _, h := range r.Results {
+		if h.Status > worst {
+			worst = h.Status
+		}
+	}
+
+	return worst
+}
+
+func checkHealth(ctx context.Context, key string, hc HealthChecker) (h Health) {
+	h.Key = key
+
+	start := time.Now()
+	status, err := hc.CheckHealth(ctx)
+	h.Duration = time.Since(start)
+	h.Status = status
+	if err != nil {
+		h.Message = err.Error()
+	}
+
+	return h
+}
+
+// HealthTransition is emitted by Watch whenever a plugin's status changes
+// between runs.
+type HealthTransition struct {
+	Key  string
+	From Status
+	To   Status
+}
+
+// Watch re-runs health checks every interval and emits a HealthTransition on
+// the returned channel each time a plugin's status changes from one run to
+// the next. Call the returned stop func to end the watch loop and close the
+// channel. interval must be greater than zero; time.NewTicker panics
+// otherwise, so Watch rejects it up front rather than starting a goroutine
+// that's guaranteed to crash the process.
+func (p *Plugins) Watch(interval time.Duration) (transitions <-chan HealthTransition, stop func(), err error) {
+	if interval <= 0 {
+		return nil, nil, fmt.Errorf("watch interval must be greater than zero, got %s", interval)
+	}
+
+	ch := make(chan HealthTransition)
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		defer close(ch)
+
+		last := make(map[string]Status)
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				report, err := p.Test(context.Background())
+				if err != nil {
+					continue
+				}
+
+				for _, h := range report.Results {
+					prev, ok := last[h.Key]
+					last[h.Key] = h.Status
+					if !ok || prev == h.Status {
+						continue
+					}
+
+					select {
+					case ch <- HealthTransition{Key: h.Key, From: prev, To: h.Status}:
+					case <-done:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	stop = func() {
+		close(done)
+	}
+
+	return ch, stop, nil
+}
@@ -0,0 +1,144 @@
+package plugins
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdbu/queue"
+)
+
+// fakeHealthPlugin is a Plugin that also implements HealthChecker, returning
+// whatever status/err the test configures. status is guarded by a mutex
+// since Watch's polling goroutine reads it concurrently with the test
+// changing it.
+type fakeHealthPlugin struct {
+	fakePlugin
+
+	mu     sync.Mutex
+	status Status
+	err    error
+}
+
+func (f *fakeHealthPlugin) CheckHealth(ctx context.Context) (Status, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status, f.err
+}
+
+func (f *fakeHealthPlugin) setStatus(s Status) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = s
+}
+
+var _ HealthChecker = &fakeHealthPlugin{}
+
+func TestReport_Status_worstWins(t *testing.T) {
+	r := Report{Results: []Health{
+		{Status: StatusOk},
+		{Status: StatusWarning},
+		{Status: StatusOk},
+	}}
+
+	if got := r.Status(); got != StatusWarning {
+		t.Fatalf("expected %v, got %v", StatusWarning, got)
+	}
+}
+
+func TestPlugins_Test_skipsNonHealthCheckers(t *testing.T) {
+	p := newPlugins()
+
+	checked := &fakeHealthPlugin{status: StatusOk}
+	checked.manifest = Manifest{ID: "checked"}
+	if err := p.Register("checked", checked); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Register("unchecked", &fakePlugin{manifest: Manifest{ID: "unchecked"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := p.Test(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Results) != 1 || report.Results[0].Key != "checked" {
+		t.Fatalf("expected exactly one result for <checked>, got %+v", report.Results)
+	}
+}
+
+func TestPlugins_TestAsync_matchesTest(t *testing.T) {
+	p := newPlugins()
+
+	ok := &fakeHealthPlugin{status: StatusOk}
+	ok.manifest = Manifest{ID: "ok"}
+	if err := p.Register("ok", ok); err != nil {
+		t.Fatal(err)
+	}
+
+	warning := &fakeHealthPlugin{status: StatusWarning}
+	warning.manifest = Manifest{ID: "warning"}
+	if err := p.Register("warning", warning); err != nil {
+		t.Fatal(err)
+	}
+
+	q := queue.New(2, 4)
+	report, err := p.TestAsync(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Status() != StatusWarning {
+		t.Fatalf("expected aggregated status %v, got %v", StatusWarning, report.Status())
+	}
+
+	if len(report.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(report.Results))
+	}
+}
+
+func TestPlugins_Watch_rejectsNonPositiveInterval(t *testing.T) {
+	p := newPlugins()
+
+	if _, _, err := p.Watch(0); err == nil {
+		t.Fatal("expected a non-positive interval to be rejected")
+	}
+
+	if _, _, err := p.Watch(-time.Second); err == nil {
+		t.Fatal("expected a negative interval to be rejected")
+	}
+}
+
+func TestPlugins_Watch_emitsTransition(t *testing.T) {
+	p := newPlugins()
+
+	hp := &fakeHealthPlugin{status: StatusOk}
+	hp.manifest = Manifest{ID: "flaky"}
+	if err := p.Register("flaky", hp); err != nil {
+		t.Fatal(err)
+	}
+
+	transitions, stop, err := p.Watch(5 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	// Watch's first tick only establishes the baseline status for each
+	// plugin; give it time to run before flipping the status so the next
+	// tick actually observes a change.
+	time.Sleep(20 * time.Millisecond)
+	hp.setStatus(StatusError)
+
+	select {
+	case tr := <-transitions:
+		if tr.Key != "flaky" || tr.From != StatusOk || tr.To != StatusError {
+			t.Fatalf("unexpected transition: %+v", tr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a health transition")
+	}
+}
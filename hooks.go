@@ -0,0 +1,16 @@
+package plugins
+
+// Initializable is implemented by plugins that need a hook which runs after
+// every plugin in the dependency graph has been loaded, for wiring that can't
+// happen until the whole set is available. Plugins.Each callers type-assert
+// for it rather than it being part of the base Plugin interface, so plugins
+// that don't need it aren't forced to implement an empty method.
+type Initializable interface {
+	OnAllLoaded() error
+}
+
+// Reloadable is implemented by plugins that support being reloaded in place,
+// e.g. after a config change, without a full Close/Load cycle.
+type Reloadable interface {
+	Reload() error
+}
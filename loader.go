@@ -0,0 +1,149 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkujhd/goloader"
+)
+
+// symbols is the name->address table goloader links a plugin object against
+// and resolves exports into. It's shared across every plugin loaded by a
+// given GoloaderDriver so that plugins can call into the Go runtime and
+// standard library, which is also why namespace below exists: two plugins
+// built from different repos that both export main.Handler would otherwise
+// shadow each other in this one map.
+type symbols map[string]uintptr
+
+var _ Driver = &GoloaderDriver{}
+
+// GoloaderDriver loads plugins in-process from compiled .o objects using
+// goloader rather than Go's own plugin package, so it isn't limited to
+// platforms -buildmode=plugin supports. See RPCDriver for the out-of-process
+// alternative.
+type GoloaderDriver struct {
+	syms symbols
+}
+
+// NewGoloaderDriver returns a GoloaderDriver with a freshly registered
+// runtime/stdlib symbol table.
+func NewGoloaderDriver() (d *GoloaderDriver, err error) {
+	d = &GoloaderDriver{syms: make(symbols)}
+	if err = goloader.RegSymbol(d.syms); err != nil {
+		return nil, fmt.Errorf("error registering symbols: %v", err)
+	}
+
+	return d, nil
+}
+
+// Load implements Driver by reading the .o object at key (the driver treats
+// the key as a filename) and linking it into the shared symbol table,
+// namespaced under key so two plugins exporting the same symbol name don't
+// collide.
+func (d *GoloaderDriver) Load(key string) (pi Plugin, err error) {
+	gp := &goloaderPlugin{key: key, driver: d}
+	if err = gp.init(); err != nil {
+		return nil, err
+	}
+
+	return gp, nil
+}
+
+// goloaderPlugin is the Plugin implementation backing a single object loaded
+// by a GoloaderDriver.
+type goloaderPlugin struct {
+	key    string
+	driver *GoloaderDriver
+}
+
+var _ Plugin = &goloaderPlugin{}
+
+// init reads the object at p.key and links it against the driver's shared
+// symbol table.
+func (p *goloaderPlugin) init() (err error) {
+	var f *os.File
+	if f, err = os.Open(p.key); err != nil {
+		return fmt.Errorf("error opening <%s>: %v", p.key, err)
+	}
+	defer f.Close()
+
+	var linker *goloader.Linker
+	if linker, err = goloader.ReadObj(f, p.key); err != nil {
+		return fmt.Errorf("error reading object <%s>: %v", p.key, err)
+	}
+
+	var module *goloader.CodeModule
+	if module, err = goloader.Load(linker, p.driver.syms); err != nil {
+		return fmt.Errorf("error loading object <%s>: %v", p.key, err)
+	}
+
+	p.namespace(module)
+	return nil
+}
+
+// namespace rewrites the object's exported main.* symbols into
+// <pluginKey>.* entries in the driver's shared symbol table.
+func (p *goloaderPlugin) namespace(module *goloader.CodeModule) {
+	for name, addr := range module.Syms {
+		if !strings.HasPrefix(name, "main.") {
+			continue
+		}
+
+		p.driver.syms[p.key+"."+strings.TrimPrefix(name, "main.")] = addr
+	}
+}
+
+// Lookup returns the address of a symbol this plugin exports. name is the
+// symbol's original main.* name; Lookup transparently resolves it against
+// this plugin's namespaced entry so callers don't need to know the key.
+func (p *goloaderPlugin) Lookup(name string) (sym Symbol, ok bool) {
+	var addr uintptr
+	if addr, ok = p.driver.syms[p.key+"."+strings.TrimPrefix(name, "main.")]; !ok {
+		return 0, false
+	}
+
+	return Symbol(addr), true
+}
+
+// Init implements Plugin by invoking the plugin's exported main.Init, if any.
+func (p *goloaderPlugin) Init(env map[string]string) error {
+	sym, ok := p.Lookup("main.Init")
+	if !ok {
+		return nil
+	}
+
+	return sym.AsInitFunc()(env)
+}
+
+// Load implements Plugin by invoking the plugin's exported main.Load, if any.
+func (p *goloaderPlugin) Load(plugins *Plugins) error {
+	sym, ok := p.Lookup("main.Load")
+	if !ok {
+		return nil
+	}
+
+	return sym.AsLoadFunc()(plugins)
+}
+
+// Backend implements Plugin by invoking the plugin's exported main.Backend,
+// if any.
+func (p *goloaderPlugin) Backend() interface{} {
+	sym, ok := p.Lookup("main.Backend")
+	if !ok {
+		return nil
+	}
+
+	return sym.AsInterfaceFunc()()
+}
+
+// Close implements Plugin by invoking the plugin's exported main.Close, if
+// any.
+func (p *goloaderPlugin) Close() error {
+	sym, ok := p.Lookup("main.Close")
+	if !ok {
+		return nil
+	}
+
+	return sym.AsErrorFunc()()
+}
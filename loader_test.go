@@ -0,0 +1,53 @@
+package plugins
+
+import "testing"
+
+// TestGoloaderDriver_namespace loads two plugins that both export main.Handler
+// and asserts each plugin's Lookup resolves its own address rather than
+// shadowing the other, the bug fixed by namespacing symbols under the
+// plugin's key.
+func TestGoloaderDriver_namespace(t *testing.T) {
+	if !loaderFixturesAvailable {
+		t.Skip("goloader object fixtures unavailable")
+	}
+
+	driver, err := NewGoloaderDriver()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	piA, err := driver.Load("./test_data/plugin_a.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	piB, err := driver.Load("./test_data/plugin_b.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpA := piA.(*goloaderPlugin)
+	gpB := piB.(*goloaderPlugin)
+
+	symA, ok := gpA.Lookup("main.Handler")
+	if !ok {
+		t.Fatal("main.Handler not found in plugin_a")
+	}
+
+	symB, ok := gpB.Lookup("main.Handler")
+	if !ok {
+		t.Fatal("main.Handler not found in plugin_b")
+	}
+
+	if symA == symB {
+		t.Fatal("expected plugin_a and plugin_b's main.Handler to resolve to different addresses")
+	}
+
+	if addr, ok := driver.syms[gpA.key+".Handler"]; !ok || Symbol(addr) != symA {
+		t.Fatal("plugin_a's Handler was not namespaced under its own key")
+	}
+
+	if addr, ok := driver.syms[gpB.key+".Handler"]; !ok || Symbol(addr) != symB {
+		t.Fatal("plugin_b's Handler was not namespaced under its own key")
+	}
+}
@@ -0,0 +1,53 @@
+package plugins
+
+import "fmt"
+
+// HostVersion is this module's own version, used to enforce a plugin's
+// declared MinHostVersion.
+var HostVersion = Version{Major: 0, Minor: 1, Patch: 0}
+
+// Manifest describes a plugin's identity and requirements. Plugins.Register
+// uses it to validate compatibility and to order Load calls by dependency.
+type Manifest struct {
+	// ID uniquely identifies the plugin, independent of the key it was
+	// registered under, so the same plugin registered under two aliases (or
+	// two different plugins registered under a colliding alias) can still be
+	// told apart.
+	ID string
+	// Version is the plugin's own semantic version.
+	Version Version
+	// MinHostVersion is the minimum HostVersion this plugin requires.
+	MinHostVersion Version
+	// Capabilities lists the interface names this plugin implements, so
+	// callers can discover plugins by what they do rather than their key.
+	Capabilities []string
+	// Requires lists the plugin keys that must be registered and loaded
+	// before this one.
+	Requires []string
+}
+
+// Manifester is implemented by plugins which declare a Manifest. Plugins
+// which don't implement it are treated as having no dependencies and no
+// minimum host version requirement.
+type Manifester interface {
+	Manifest() Manifest
+}
+
+// validate checks the manifest itself, plus - when the registration key
+// carried an explicit path@vX.Y.Z pin (see ParseKey) - that the plugin's
+// declared Version actually matches what the caller pinned.
+func (m Manifest) validate(pinned Version, hasPin bool) error {
+	if m.ID == "" {
+		return fmt.Errorf("manifest is missing an ID")
+	}
+
+	if HostVersion.Compare(m.MinHostVersion) < 0 {
+		return fmt.Errorf("plugin <%s> requires host version %s, running %s", m.ID, m.MinHostVersion, HostVersion)
+	}
+
+	if hasPin && m.Version.Compare(pinned) != 0 {
+		return fmt.Errorf("plugin <%s> declares version %s, which does not match the pinned version %s", m.ID, m.Version, pinned)
+	}
+
+	return nil
+}
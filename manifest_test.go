@@ -0,0 +1,156 @@
+package plugins
+
+import "testing"
+
+// fakePlugin is a minimal Plugin used by tests in this package. It always
+// implements Manifester and Initializable; tests that don't care leave
+// manifest and onAllLoaded at their zero values.
+type fakePlugin struct {
+	manifest Manifest
+
+	onAllLoaded func() error
+}
+
+func (f *fakePlugin) Init(map[string]string) error { return nil }
+func (f *fakePlugin) Load(*Plugins) error           { return nil }
+func (f *fakePlugin) Backend() interface{}          { return nil }
+func (f *fakePlugin) Close() error                  { return nil }
+
+func (f *fakePlugin) Manifest() Manifest {
+	return f.manifest
+}
+
+func (f *fakePlugin) OnAllLoaded() error {
+	if f.onAllLoaded == nil {
+		return nil
+	}
+
+	return f.onAllLoaded()
+}
+
+var _ Manifester = &fakePlugin{}
+var _ Initializable = &fakePlugin{}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b Version
+		want int
+	}{
+		{Version{1, 0, 0}, Version{1, 0, 0}, 0},
+		{Version{1, 0, 0}, Version{1, 0, 1}, -1},
+		{Version{1, 1, 0}, Version{1, 0, 9}, 1},
+		{Version{2, 0, 0}, Version{1, 9, 9}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersion_invalid(t *testing.T) {
+	if _, err := ParseVersion("not-a-version"); err == nil {
+		t.Fatal("expected an error for a malformed version")
+	}
+}
+
+func TestManifest_validate_versionPinMismatch(t *testing.T) {
+	m := Manifest{ID: "test-plugin", Version: Version{1, 0, 0}, MinHostVersion: Version{0, 1, 0}}
+
+	if err := m.validate(Version{1, 0, 0}, true); err != nil {
+		t.Fatalf("expected a matching pin to validate, got: %v", err)
+	}
+
+	if err := m.validate(Version{1, 2, 0}, true); err == nil {
+		t.Fatal("expected a mismatched pin to be rejected")
+	}
+}
+
+func TestManifest_validate_minHostVersion(t *testing.T) {
+	m := Manifest{ID: "test-plugin", MinHostVersion: Version{99, 0, 0}}
+	if err := m.validate(Version{}, false); err == nil {
+		t.Fatal("expected a MinHostVersion above HostVersion to be rejected")
+	}
+}
+
+func TestPlugins_Register_duplicateID(t *testing.T) {
+	p := newPlugins()
+
+	a := &fakePlugin{manifest: Manifest{ID: "dup"}}
+	b := &fakePlugin{manifest: Manifest{ID: "dup"}}
+
+	if err := p.Register("a", a); err != nil {
+		t.Fatalf("unexpected error registering a: %v", err)
+	}
+
+	if err := p.Register("b", b); err == nil {
+		t.Fatal("expected registering a duplicate manifest ID to fail")
+	}
+}
+
+func TestPlugins_sortedKeys_dependencyOrder(t *testing.T) {
+	p := newPlugins()
+
+	if err := p.Register("base", &fakePlugin{manifest: Manifest{ID: "base"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Register("dependent", &fakePlugin{manifest: Manifest{ID: "dependent", Requires: []string{"base"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	if err := p.Each(func(pi Plugin) error {
+		fp := pi.(*fakePlugin)
+		order = append(order, fp.manifest.ID)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(order) != 2 || order[0] != "base" || order[1] != "dependent" {
+		t.Fatalf("expected [base dependent], got %v", order)
+	}
+}
+
+func TestPlugins_sortedKeys_cycle(t *testing.T) {
+	p := newPlugins()
+
+	if err := p.Register("a", &fakePlugin{manifest: Manifest{ID: "a", Requires: []string{"b"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Register("b", &fakePlugin{manifest: Manifest{ID: "b", Requires: []string{"a"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Each(func(Plugin) error { return nil }); err == nil {
+		t.Fatal("expected a dependency cycle to be detected")
+	}
+}
+
+func TestPlugins_Load_callsOnAllLoaded(t *testing.T) {
+	p := newPlugins()
+
+	var called bool
+	fp := &fakePlugin{
+		manifest: Manifest{ID: "hooked"},
+		onAllLoaded: func() error {
+			called = true
+			return nil
+		},
+	}
+
+	if err := p.Register("hooked", fp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !called {
+		t.Fatal("expected OnAllLoaded to be called after Load")
+	}
+}
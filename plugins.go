@@ -1,6 +1,7 @@
 package plugins
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	"github.com/gdbu/scribe"
 
 	"github.com/hatchify/errors"
+	"github.com/vroomy/plugins/dist"
 )
 
 const (
@@ -41,6 +43,26 @@ func Loaded() map[string]Plugin {
 	return p.Loaded()
 }
 
+// Each calls fn for every registered plugin in dependency order
+func Each(fn func(Plugin) error) error {
+	return p.Each(fn)
+}
+
+// Load calls Load(*Plugins) on every registered plugin in dependency order
+func Load() error {
+	return p.Load()
+}
+
+// Uninstall closes and removes the plugin registered under key
+func Uninstall(key string) error {
+	return p.Uninstall(key)
+}
+
+// Install resolves, loads, and registers the plugin identified by key
+func Install(key string, driver Driver) (Plugin, error) {
+	return p.Install(key, driver)
+}
+
 // Backend will associated the backend of the requested key
 func Backend(key string, backend interface{}) error {
 	return p.Backend(key, backend)
@@ -50,6 +72,8 @@ func newPlugins() *Plugins {
 	var p Plugins
 	p.out = scribe.New("Plugins")
 	p.pm = make(map[string]Plugin)
+	p.manifests = make(map[string]Manifest)
+	p.byID = make(map[string]string)
 	return &p
 }
 
@@ -58,11 +82,71 @@ type Plugins struct {
 	mu  sync.RWMutex
 	out *scribe.Scribe
 
-	pm map[string]Plugin
+	pm        map[string]Plugin
+	manifests map[string]Manifest // keyed by plugin key, not manifest ID
+	byID      map[string]string   // manifest ID -> key, rejects duplicate IDs
+	order     []string            // registration order, input to the dependency sort
+	cache     ArtifactCache
+	resolver  *dist.Resolver
 
 	closed bool
 }
 
+// ArtifactCache is garbage collected by Uninstall when a plugin backed by a
+// distributed artifact (see the dist subpackage) is removed. It's optional;
+// plugins registered without going through dist never need one configured.
+type ArtifactCache interface {
+	Remove(key string) error
+}
+
+// UseCache registers the artifact cache Uninstall should garbage-collect
+// entries from.
+func (p *Plugins) UseCache(c ArtifactCache) {
+	p.mu.Lock()
+	p.cache = c
+	p.mu.Unlock()
+}
+
+// UseResolver registers the dist.Resolver Install should use to turn a key
+// into a compiled artifact. Plugins that are constructed and Registered
+// directly by the caller never need one configured.
+func (p *Plugins) UseResolver(r *dist.Resolver) {
+	p.mu.Lock()
+	p.resolver = r
+	p.mu.Unlock()
+}
+
+// Install resolves key to a compiled plugin artifact (building it from
+// source, downloading a prebuilt copy, or hitting the content-addressed
+// cache, per UseResolver's dist.Resolver), loads the result with driver, and
+// Registers it under key. This is the entrypoint that makes a key like
+// "github.com/user/repo/plugin@v1.2.3" actually resolve to something
+// runnable, rather than requiring the caller to have already built it.
+func (p *Plugins) Install(key string, driver Driver) (pi Plugin, err error) {
+	p.mu.RLock()
+	resolver := p.resolver
+	p.mu.RUnlock()
+
+	if resolver == nil {
+		return nil, fmt.Errorf("no resolver configured, call UseResolver first")
+	}
+
+	var artifactPath string
+	if artifactPath, err = resolver.Resolve(key); err != nil {
+		return nil, fmt.Errorf("error resolving <%s>: %v", key, err)
+	}
+
+	if pi, err = driver.Load(artifactPath); err != nil {
+		return nil, fmt.Errorf("error loading <%s>: %v", key, err)
+	}
+
+	if err = p.Register(key, pi); err != nil {
+		return nil, err
+	}
+
+	return pi, nil
+}
+
 // New will load a new plugin by plugin key
 // The following formats are accepted as keys:
 //	- path/to/file/plugin.so
@@ -80,7 +164,128 @@ func (p *Plugins) Register(key string, pi Plugin) (err error) {
 		return fmt.Errorf("plugin with the key of <%s> has already been loaded", key)
 	}
 
+	var (
+		pinnedVersion Version
+		hasPin        bool
+	)
+
+	if _, _, pin := ParseKey(key); pin != "" {
+		if pinnedVersion, err = ParseVersion(pin); err != nil {
+			return fmt.Errorf("invalid version pin for <%s>: %v", key, err)
+		}
+
+		hasPin = true
+	}
+
+	var manifest Manifest
+	if mp, ok := pi.(Manifester); ok {
+		manifest = mp.Manifest()
+		if err = manifest.validate(pinnedVersion, hasPin); err != nil {
+			return fmt.Errorf("invalid manifest for <%s>: %v", key, err)
+		}
+
+		if existingKey, ok := p.byID[manifest.ID]; ok {
+			return fmt.Errorf("plugin with the id of <%s> has already been registered as <%s>", manifest.ID, existingKey)
+		}
+
+		p.byID[manifest.ID] = key
+	}
+
 	p.pm[key] = pi
+	p.manifests[key] = manifest
+	p.order = append(p.order, key)
+	return
+}
+
+// Each calls fn for every registered plugin in dependency order (a plugin
+// declaring Requires runs after the keys it depends on), stopping at the
+// first error.
+func (p *Plugins) Each(fn func(Plugin) error) (err error) {
+	p.mu.RLock()
+	order, sortErr := p.sortedKeys()
+	pm := make(map[string]Plugin, len(p.pm))
+	for key, pi := range p.pm {
+		pm[key] = pi
+	}
+	p.mu.RUnlock()
+
+	if sortErr != nil {
+		return sortErr
+	}
+
+	for _, key := range order {
+		if err = fn(pm[key]); err != nil {
+			return fmt.Errorf("error processing <%s>: %v", key, err)
+		}
+	}
+
+	return
+}
+
+// Load calls Load(p) on every registered plugin in dependency order, then
+// makes a second dependency-ordered pass calling OnAllLoaded on every plugin
+// that implements Initializable, now that the whole graph is available.
+func (p *Plugins) Load() (err error) {
+	if err = p.Each(func(pi Plugin) error {
+		return pi.Load(p)
+	}); err != nil {
+		return err
+	}
+
+	return p.Each(func(pi Plugin) error {
+		init, ok := pi.(Initializable)
+		if !ok {
+			return nil
+		}
+
+		return init.OnAllLoaded()
+	})
+}
+
+// sortedKeys topologically sorts registered plugin keys by their manifest's
+// Requires list, falling back to registration order for plugins with no
+// declared dependencies. The caller must hold (at least) a read lock.
+func (p *Plugins) sortedKeys() (sorted []string, err error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(p.order))
+	sorted = make([]string, 0, len(p.order))
+
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected at <%s>", key)
+		}
+
+		state[key] = visiting
+		for _, dep := range p.manifests[key].Requires {
+			if _, ok := p.pm[dep]; !ok {
+				return fmt.Errorf("plugin <%s> requires <%s>, which has not been registered", key, dep)
+			}
+
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		state[key] = visited
+		sorted = append(sorted, key)
+		return nil
+	}
+
+	for _, key := range p.order {
+		if err = visit(key); err != nil {
+			return nil, err
+		}
+	}
+
 	return
 }
 
@@ -155,44 +360,117 @@ func (p *Plugins) Backend(key string, backend interface{}) (err error) {
 	return
 }
 
-// Test will test all of the plugins
-func (p *Plugins) Test() (err error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// Test runs a health check against every registered plugin that implements
+// HealthChecker, sequentially, and returns the aggregated Report. Plugins
+// which don't implement HealthChecker are skipped rather than errored.
+func (p *Plugins) Test(ctx context.Context) (report Report, err error) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return report, errors.ErrIsClosed
+	}
+
+	pm := make(map[string]Plugin, len(p.pm))
+	for key, pi := range p.pm {
+		pm[key] = pi
+	}
+	p.mu.RUnlock()
+
+	for key, pi := range pm {
+		hc, ok := pi.(HealthChecker)
+		if !ok {
+			continue
+		}
+
+		report.Results = append(report.Results, checkHealth(ctx, key, hc))
+	}
 
-	//for _, pi := range p.pm {
-	// TODO: Resolve test stuff here
-	//if err = pi.test(); err != nil {
-	//	return
-	//}
-	//}
+	return report, nil
+}
+
+// TestAsync runs the same checks as Test, but concurrently through q.
+func (p *Plugins) TestAsync(ctx context.Context, q *queue.Queue) (report Report, err error) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return report, errors.ErrIsClosed
+	}
+
+	pm := make(map[string]Plugin, len(p.pm))
+	for key, pi := range p.pm {
+		pm[key] = pi
+	}
+	p.mu.RUnlock()
 
-	return errors.Error("testing has not yet been implemented")
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for key, pi := range pm {
+		hc, ok := pi.(HealthChecker)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		q.New(func(key string, hc HealthChecker) func() {
+			return func() {
+				defer wg.Done()
+				h := checkHealth(ctx, key, hc)
+
+				mu.Lock()
+				report.Results = append(report.Results, h)
+				mu.Unlock()
+			}
+		}(key, hc))
+	}
 
+	wg.Wait()
+	return report, nil
 }
 
-// TestAsync will test all of the plugins asynchronously
-func (p *Plugins) TestAsync(q *queue.Queue) (err error) {
+// Uninstall closes the plugin registered under key, removes it from the
+// registry, and garbage-collects its cached artifact if UseCache was called.
+func (p *Plugins) Uninstall(key string) (err error) {
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	pi, ok := p.pm[key]
+	if !ok {
+		p.mu.Unlock()
+		return fmt.Errorf("plugin with key of <%s> has not been registered", key)
+	}
+
+	manifest := p.manifests[key]
+	delete(p.pm, key)
+	delete(p.manifests, key)
+	if manifest.ID != "" {
+		delete(p.byID, manifest.ID)
+	}
+	p.order = removeKey(p.order, key)
+	cache := p.cache
+	p.mu.Unlock()
+
+	if err = pi.Close(); err != nil {
+		return fmt.Errorf("error closing <%s>: %v", key, err)
+	}
+
+	if cache != nil {
+		if err = cache.Remove(key); err != nil {
+			return fmt.Errorf("error removing cached artifact for <%s>: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func removeKey(keys []string, target string) []string {
+	for i, key := range keys {
+		if key == target {
+			return append(keys[:i], keys[i+1:]...)
+		}
+	}
 
-	//var wg sync.WaitGroup
-	//wg.Add(len(p.pm))
-	//
-	//var errs errors.ErrorList
-	//for _, pi := range p.pm {
-	//	q.New(func(pi Plugin) func() {
-	//		return func() {
-	//			defer wg.Done()
-	//			// Fix test stuff here
-	//		}
-	//	}(pi))
-	//}
-	//
-	//wg.Wait()
-	//
-	//return errs.Err()
-	return errors.Error("testing has not yet been implemented")
+	return keys
 }
 
 // Close will close plugins
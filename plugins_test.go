@@ -3,70 +3,67 @@ package plugins
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"testing"
-
-	"github.com/pkujhd/goloader"
-)
-
-var (
-	testPlugins *Plugins
-	testDir     = "./test_data"
 )
 
-func testInit() (p *Plugins, err error) {
-	if err = os.Mkdir(testDir, 0744); err != nil {
-		return
+// loaderFixturesAvailable is set by TestMain once it's known whether this
+// environment can build the goloader object fixtures under ./test_data
+// (doing so runs `go tool compile` via ./test_data/build.sh). Tests
+// that need those fixtures skip themselves when it's false instead of
+// failing, since plenty of CI/dev environments can't build them.
+var loaderFixturesAvailable bool
+
+func TestMain(m *testing.M) {
+	if err := buildLoaderFixtures(); err != nil {
+		fmt.Fprintf(os.Stderr, "skipping goloader fixture tests: %v\n", err)
+	} else {
+		loaderFixturesAvailable = true
 	}
 
-	return New(testDir)
+	os.Exit(m.Run())
 }
 
-func testTeardown() (err error) {
-	return os.RemoveAll(testDir)
-}
-
-func TestPlugin_init(t *testing.T) {
-	var (
-		p   Plugin
-		err error
-	)
+func buildLoaderFixtures() error {
+	cmd := exec.Command("sh", "./test_data/build.sh")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
 
-	p.filename = "./foo.o"
+	return nil
+}
 
-	syms := make(symbols)
-	if err = goloader.RegSymbol(syms); err != nil {
-		err = fmt.Errorf("error registering symbol: %v", err)
-		return
+func TestGoloaderDriver_singlePlugin(t *testing.T) {
+	if !loaderFixturesAvailable {
+		t.Skip("goloader object fixtures unavailable")
 	}
 
-	if err = p.init(syms); err != nil {
+	driver, err := NewGoloaderDriver()
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	var (
-		sym Symbol
-		ok  bool
-	)
-
-	if sym, ok = p.Lookup("main.main"); !ok {
-		t.Fatal("fn of main.main not found")
+	pi, err := driver.Load("./test_data/plugin_a.o")
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	fn := sym.AsEmptyFunc()
-	if fn == nil {
-		return
+	gp, ok := pi.(*goloaderPlugin)
+	if !ok {
+		t.Fatalf("expected *goloaderPlugin, got %T", pi)
 	}
 
-	fn()
-
-	if sym, ok = p.Lookup("main.BigInt"); !ok {
-		t.Fatal("fn of main.main not found")
+	sym, ok := gp.Lookup("main.Handler")
+	if !ok {
+		t.Fatal("main.Handler not found")
 	}
 
-	bigIntFn := sym.AsInterfaceFunc()
-	if bigIntFn == nil {
-		return
+	fn := sym.AsInterfaceFunc()
+	if fn == nil {
+		t.Fatal("expected a non-nil function pointer")
 	}
 
-	fmt.Println("Value?", bigIntFn())
+	if v := fn(); v != "plugin_a" {
+		t.Fatalf("expected %q, got %v", "plugin_a", v)
+	}
 }
@@ -0,0 +1,84 @@
+package plugins
+
+import (
+	"fmt"
+	"io"
+)
+
+// rpcHandshakeCookie and rpcProtocolVersion are exchanged between host and
+// plugin before any RPC traffic is trusted. They guard against a misconfigured
+// Command accidentally launching something that isn't a plugins-RPC binary.
+const (
+	rpcHandshakeCookie = "VROOMY_PLUGIN_MAGIC_COOKIE_V1"
+	rpcProtocolVersion = 1
+)
+
+// rpcHandshake is written by the child as a single gob value on stdout
+// immediately after boot, before the stream is handed over to net/rpc.
+type rpcHandshake struct {
+	Cookie  string
+	Version int
+}
+
+func (h rpcHandshake) validate() error {
+	if h.Cookie != rpcHandshakeCookie {
+		return fmt.Errorf("invalid handshake cookie <%s>", h.Cookie)
+	}
+
+	if h.Version != rpcProtocolVersion {
+		return fmt.Errorf("unsupported protocol version <%d>, expected <%d>", h.Version, rpcProtocolVersion)
+	}
+
+	return nil
+}
+
+// rpcService is the name the plugin's Plugin implementation is registered
+// under on its net/rpc server.
+const rpcService = "Plugin"
+
+// rpcInitArgs is the Init call marshaled for the wire.
+type rpcInitArgs struct {
+	Env map[string]string
+}
+
+// rpcCallArgs invokes an arbitrary method on the plugin's Backend() by name,
+// which is how RPCBackend reaches backend types this package has no
+// compile-time knowledge of. Args/Reply are gob values, so any concrete type
+// they carry must be registered with gob.Register by the plugin and the host
+// alike.
+type rpcCallArgs struct {
+	Method string
+	Args   []interface{}
+}
+
+// rpcCallReply carries the Backend method's return values back to the caller.
+// Err is a plain string, rather than the error interface, since gob cannot
+// decode into an interface without a concrete registered type.
+type rpcCallReply struct {
+	Values []interface{}
+	Err    string
+}
+
+func rpcErrString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func rpcErrFromString(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	return fmt.Errorf(s)
+}
+
+// rwc adapts a separately-obtained Reader and WriteCloser (what you get from
+// both os.Stdin/os.Stdout and exec.Cmd's StdinPipe/StdoutPipe) into the single
+// io.ReadWriteCloser that the handshake and net/rpc both expect.
+type rwc struct {
+	io.Reader
+	io.WriteCloser
+}
@@ -0,0 +1,315 @@
+package plugins
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os/exec"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gdbu/scribe"
+)
+
+const (
+	rpcRestartBaseDelay = time.Second
+	rpcRestartMaxDelay  = time.Minute
+)
+
+var _ Driver = &RPCDriver{}
+
+// RPCDriver loads a Plugin by running it as a separate OS process and talking
+// to it over RPC, rather than loading it in-process via GoloaderDriver. It
+// trades an RPC hop for isolation from the host process, and restarts the
+// child if it crashes.
+type RPCDriver struct {
+	// Command builds the *exec.Cmd used to (re)start the plugin. It is called
+	// once per start attempt so a fresh, unstarted *exec.Cmd is always used.
+	Command func() *exec.Cmd
+	// MaxRestarts caps the number of times a crashed plugin is restarted. Zero
+	// means unlimited.
+	MaxRestarts int
+	// NewBackend adapts an RPCBackend into the concrete type Backend()
+	// returns. Go can't synthesize a type that implements an arbitrary
+	// interface at runtime, so this adapter is still written once per plugin
+	// interface (see RPCBackend.Proxy) rather than per plugin instance; if
+	// NewBackend is nil, Backend() returns the RPCBackend itself.
+	NewBackend func(*RPCBackend) interface{}
+
+	out *scribe.Scribe
+}
+
+// NewRPCDriver returns an RPCDriver which starts plugin processes using cmd.
+func NewRPCDriver(cmd func() *exec.Cmd) *RPCDriver {
+	return &RPCDriver{
+		Command: cmd,
+		out:     scribe.New("RPCDriver"),
+	}
+}
+
+// Load starts the plugin process, performs the handshake, and returns a
+// Plugin backed by the resulting RPC connection. The process is supervised
+// for the lifetime of the returned Plugin; a crash triggers a restart with
+// exponential backoff until Close is called or MaxRestarts is exceeded.
+func (d *RPCDriver) Load(key string) (pi Plugin, err error) {
+	rp := &rpcPlugin{
+		key:    key,
+		driver: d,
+	}
+
+	if err = rp.spawn(); err != nil {
+		return
+	}
+
+	go rp.supervise()
+	pi = rp
+	return
+}
+
+// rpcPlugin is the host-side Plugin implementation backed by a child process
+// speaking the RPCDriver protocol.
+type rpcPlugin struct {
+	mu sync.Mutex
+
+	key    string
+	driver *RPCDriver
+
+	cmd     *exec.Cmd
+	client  *rpc.Client
+	backend *RPCBackend
+
+	restarts int
+	closed   bool
+}
+
+// spawn starts the child process, performs the handshake, and establishes the
+// RPC client. spawn takes p.mu itself, so the caller must not hold it.
+func (p *rpcPlugin) spawn() (err error) {
+	cmd := p.driver.Command()
+
+	var stdin io.WriteCloser
+	if stdin, err = cmd.StdinPipe(); err != nil {
+		return fmt.Errorf("error opening stdin pipe: %v", err)
+	}
+
+	var stdout io.ReadCloser
+	if stdout, err = cmd.StdoutPipe(); err != nil {
+		return fmt.Errorf("error opening stdout pipe: %v", err)
+	}
+
+	var stderr io.ReadCloser
+	if stderr, err = cmd.StderrPipe(); err != nil {
+		return fmt.Errorf("error opening stderr pipe: %v", err)
+	}
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("error starting plugin <%s>: %v", p.key, err)
+	}
+
+	go p.pipeStderr(stderr)
+
+	conn := rwc{Reader: stdout, WriteCloser: stdin}
+
+	var hs rpcHandshake
+	if err = gob.NewDecoder(conn).Decode(&hs); err != nil {
+		cmd.Process.Kill()
+		return fmt.Errorf("error reading handshake from <%s>: %v", p.key, err)
+	}
+
+	if err = hs.validate(); err != nil {
+		cmd.Process.Kill()
+		return fmt.Errorf("handshake failed for <%s>: %v", p.key, err)
+	}
+
+	client := rpc.NewClient(conn)
+
+	p.mu.Lock()
+	p.cmd = cmd
+	p.client = client
+	p.backend = &RPCBackend{client: client}
+	p.mu.Unlock()
+	return
+}
+
+func (p *rpcPlugin) pipeStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		p.driver.out.Errorf("[%s] %s", p.key, scanner.Text())
+	}
+}
+
+// supervise waits for the child to exit and, unless the plugin was closed
+// deliberately, restarts it with exponential backoff.
+func (p *rpcPlugin) supervise() {
+	for {
+		p.mu.Lock()
+		cmd := p.cmd
+		p.mu.Unlock()
+
+		cmd.Wait()
+
+		p.mu.Lock()
+		closed := p.closed
+		restarts := p.restarts
+		p.mu.Unlock()
+
+		if closed {
+			return
+		}
+
+		if p.driver.MaxRestarts > 0 && restarts >= p.driver.MaxRestarts {
+			p.driver.out.Errorf("plugin <%s> exited and exceeded its restart budget", p.key)
+			return
+		}
+
+		delay := rpcRestartBaseDelay << restarts
+		if delay > rpcRestartMaxDelay || delay <= 0 {
+			delay = rpcRestartMaxDelay
+		}
+
+		p.driver.out.Warningf("plugin <%s> exited unexpectedly, restarting in %s", p.key, delay)
+		time.Sleep(delay)
+
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return
+		}
+		p.restarts++
+		p.mu.Unlock()
+
+		if err := p.spawn(); err != nil {
+			p.driver.out.Errorf("error restarting plugin <%s>: %v", p.key, err)
+		}
+	}
+}
+
+// Init proxies Plugin.Init to the child process.
+func (p *rpcPlugin) Init(env map[string]string) error {
+	p.mu.Lock()
+	client := p.client
+	p.mu.Unlock()
+
+	return client.Call(rpcService+".Init", rpcInitArgs{Env: env}, &struct{}{})
+}
+
+// Load is a no-op for RPC-backed plugins; by the time Load is called the
+// process is already running and initialized, and *Plugins itself lives in
+// the host's address space so it cannot be handed across the wire.
+func (p *rpcPlugin) Load(*Plugins) error {
+	return nil
+}
+
+// Backend returns driver.NewBackend's adapter over the RPCBackend, if one was
+// configured, so that Plugins.Backend's type check can succeed for RPC-backed
+// plugins the same way it does for in-process ones. With no NewBackend
+// configured, it returns the RPCBackend itself.
+func (p *rpcPlugin) Backend() interface{} {
+	p.mu.Lock()
+	backend := p.backend
+	p.mu.Unlock()
+
+	if p.driver.NewBackend != nil {
+		return p.driver.NewBackend(backend)
+	}
+
+	return backend
+}
+
+// Close stops supervising the child process and shuts it down.
+func (p *rpcPlugin) Close() (err error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+
+	p.closed = true
+	client := p.client
+	cmd := p.cmd
+	p.mu.Unlock()
+
+	err = client.Call(rpcService+".Close", struct{}{}, &struct{}{})
+	client.Close()
+	cmd.Process.Kill()
+	return
+}
+
+// RPCBackend is what rpcPlugin.Backend() returns (possibly wrapped by
+// RPCDriver.NewBackend). Go has no way to synthesize, at runtime, a type
+// whose method set satisfies an arbitrary interface, so a concrete adapter
+// type is still required to make Plugins.Backend's type check pass — but
+// Proxy generates that adapter's method bodies via reflect.MakeFunc, so the
+// adapter itself is just plumbing:
+//
+//	type fooClient struct{ b *plugins.RPCBackend }
+//
+//	func (f *fooClient) Foo(n int) (s string) {
+//		fn := f.b.Proxy("Foo", reflect.TypeOf(f.Foo)).Interface().(func(int) string)
+//		return fn(n)
+//	}
+//
+// Call remains available as a lower-level escape hatch for code that would
+// rather invoke methods by name directly.
+type RPCBackend struct {
+	client *rpc.Client
+}
+
+// Call invokes method on the plugin's Backend() value and returns its return
+// values. Any non-builtin argument or return type must have been registered
+// with gob.Register by both sides beforehand.
+func (b *RPCBackend) Call(method string, args ...interface{}) (values []interface{}, err error) {
+	var reply rpcCallReply
+	if err = b.client.Call(rpcService+".Call", rpcCallArgs{Method: method, Args: args}, &reply); err != nil {
+		return
+	}
+
+	if err = rpcErrFromString(reply.Err); err != nil {
+		return
+	}
+
+	values = reply.Values
+	return
+}
+
+// Proxy builds, via reflect.MakeFunc, a function value of type fnType that
+// marshals its arguments, calls method on the plugin's Backend() over RPC,
+// and unmarshals the reply into fnType's return values. fnType's last return
+// value may be the error interface; every other return value's type must
+// match what the plugin actually returns for that position.
+func (b *RPCBackend) Proxy(method string, fnType reflect.Type) reflect.Value {
+	return reflect.MakeFunc(fnType, func(in []reflect.Value) (out []reflect.Value) {
+		args := make([]interface{}, len(in))
+		for i, v := range in {
+			args[i] = v.Interface()
+		}
+
+		values, callErr := b.Call(method, args...)
+
+		numOut := fnType.NumOut()
+		out = make([]reflect.Value, numOut)
+		for i := 0; i < numOut; i++ {
+			outType := fnType.Out(i)
+
+			if outType == errType {
+				out[i] = reflect.New(outType).Elem()
+				if callErr != nil {
+					out[i].Set(reflect.ValueOf(callErr))
+				}
+
+				continue
+			}
+
+			if i < len(values) && values[i] != nil {
+				out[i] = reflect.ValueOf(values[i])
+			} else {
+				out[i] = reflect.Zero(outType)
+			}
+		}
+
+		return out
+	})
+}
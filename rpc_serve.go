@@ -0,0 +1,95 @@
+package plugins
+
+import (
+	"encoding/gob"
+	"fmt"
+	"net/rpc"
+	"os"
+	"reflect"
+)
+
+var errType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Serve boots the RPC server for pi over stdin/stdout and blocks until the
+// host closes the connection. It is the entry point a plugin built to run
+// out-of-process uses instead of exporting symbols for goloader:
+//
+//	func main() {
+//		if err := plugins.Serve(&myPlugin{}); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// The host launches this binary as a child process via RPCDriver, so stdout
+// must not be written to by anything other than Serve.
+func Serve(pi Plugin) (err error) {
+	conn := rwc{Reader: os.Stdin, WriteCloser: os.Stdout}
+
+	enc := gob.NewEncoder(conn)
+	hs := rpcHandshake{Cookie: rpcHandshakeCookie, Version: rpcProtocolVersion}
+	if err = enc.Encode(hs); err != nil {
+		return fmt.Errorf("error writing handshake: %v", err)
+	}
+
+	srv := rpc.NewServer()
+	if err = srv.RegisterName(rpcService, &pluginRPCServer{pi: pi}); err != nil {
+		return fmt.Errorf("error registering plugin service: %v", err)
+	}
+
+	srv.ServeConn(conn)
+	return
+}
+
+// pluginRPCServer exposes a Plugin over net/rpc. It runs inside the child
+// process started by RPCDriver.
+type pluginRPCServer struct {
+	pi Plugin
+}
+
+// Init proxies Plugin.Init.
+func (s *pluginRPCServer) Init(args rpcInitArgs, reply *struct{}) error {
+	return s.pi.Init(args.Env)
+}
+
+// Close proxies Plugin.Close.
+func (s *pluginRPCServer) Close(args struct{}, reply *struct{}) error {
+	return s.pi.Close()
+}
+
+// Call invokes a method on the plugin's Backend() by name, used by RPCBackend
+// to support backends this package has no compile-time knowledge of. Any
+// concrete argument/return type that isn't a Go builtin must be registered
+// with gob.Register by the plugin (and the host) before it can cross the
+// wire this way.
+func (s *pluginRPCServer) Call(args rpcCallArgs, reply *rpcCallReply) error {
+	backend := s.pi.Backend()
+	if backend == nil {
+		return fmt.Errorf("plugin backend is nil")
+	}
+
+	method := reflect.ValueOf(backend).MethodByName(args.Method)
+	if !method.IsValid() {
+		return fmt.Errorf("backend has no method <%s>", args.Method)
+	}
+
+	in := make([]reflect.Value, len(args.Args))
+	for i, a := range args.Args {
+		in[i] = reflect.ValueOf(a)
+	}
+
+	out := method.Call(in)
+	reply.Values = make([]interface{}, 0, len(out))
+	for _, o := range out {
+		if o.Type() == errType {
+			if !o.IsNil() {
+				reply.Err = rpcErrString(o.Interface().(error))
+			}
+
+			continue
+		}
+
+		reply.Values = append(reply.Values, o.Interface())
+	}
+
+	return nil
+}
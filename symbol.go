@@ -19,3 +19,13 @@ func (s Symbol) AsInterfaceFunc() (fn func() interface{}) {
 func (s Symbol) AsErrorFunc() (fn func() error) {
 	return *(*func() error)(unsafe.Pointer(&s))
 }
+
+// AsInitFunc will return the Symbol as a Plugin.Init function
+func (s Symbol) AsInitFunc() (fn func(map[string]string) error) {
+	return *(*func(map[string]string) error)(unsafe.Pointer(&s))
+}
+
+// AsLoadFunc will return the Symbol as a Plugin.Load function
+func (s Symbol) AsLoadFunc() (fn func(*Plugins) error) {
+	return *(*func(*Plugins) error)(unsafe.Pointer(&s))
+}
@@ -0,0 +1,10 @@
+package main
+
+// Handler is looked up by the host through goloaderPlugin.Lookup. Both
+// plugin_a and plugin_b export a Handler with the same name so the
+// namespacing in loader.go has something to actually disambiguate.
+func Handler() interface{} {
+	return "plugin_a"
+}
+
+func main() {}
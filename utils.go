@@ -5,11 +5,21 @@ import (
 	"strings"
 )
 
-// ParseKey returns stripped gitUrl and plugin alias
-func ParseKey(key string) (newKey, alias string) {
+// ParseKey returns stripped gitUrl, plugin alias, and pinned version (if any).
+// The key may carry an explicit version pin as path@vX.Y.Z, in which case
+// version is returned so Plugins.Register can validate it against the
+// plugin's own Manifest.
+func ParseKey(key string) (newKey, alias, version string) {
 	spl := strings.Split(key, " as ")
 	// Set key as the first part of the split
 	newKey = spl[0]
+
+	// Check to see if a version was pinned
+	if idx := strings.Index(newKey, "@"); idx != -1 {
+		version = newKey[idx+1:]
+		newKey = newKey[:idx]
+	}
+
 	// Check to see if an alias was provided
 	if len(spl) > 1 {
 		// Alias was provided, set the alias value
@@ -17,7 +27,6 @@ func ParseKey(key string) (newKey, alias string) {
 	} else {
 		_, name := path.Split(newKey)
 		alias = strings.Split(name, "-")[0]
-		alias = strings.Split(alias, "@")[0]
 		alias = strings.Split(alias, "#")[0]
 	}
 	return
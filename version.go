@@ -0,0 +1,63 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version of the form vMAJOR.MINOR.PATCH.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses a semantic version string. A leading "v" is optional.
+func ParseVersion(s string) (v Version, err error) {
+	trimmed := strings.TrimPrefix(s, "v")
+	parts := strings.SplitN(trimmed, ".", 3)
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version <%s>, expected MAJOR.MINOR.PATCH", s)
+	}
+
+	if v.Major, err = strconv.Atoi(parts[0]); err != nil {
+		return v, fmt.Errorf("invalid major version in <%s>: %v", s, err)
+	}
+
+	if v.Minor, err = strconv.Atoi(parts[1]); err != nil {
+		return v, fmt.Errorf("invalid minor version in <%s>: %v", s, err)
+	}
+
+	if v.Patch, err = strconv.Atoi(parts[2]); err != nil {
+		return v, fmt.Errorf("invalid patch version in <%s>: %v", s, err)
+	}
+
+	return
+}
+
+// String returns the version as vMAJOR.MINOR.PATCH.
+func (v Version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0, or 1 as v is less than, equal to, or greater than o.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major != o.Major:
+		return compareInt(v.Major, o.Major)
+	case v.Minor != o.Minor:
+		return compareInt(v.Minor, o.Minor)
+	default:
+		return compareInt(v.Patch, o.Patch)
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}